@@ -18,7 +18,19 @@ func (sp *SomeProcessor) Process(ch <-chan int) {
 		return
 	default:
 	}
+	changed, unsubscribe := sp.StateChanged()
+	defer unsubscribe()
 	for {
+		if sp.State() == startstopper.Paused {
+			// Stop consuming from ch until resumed, without losing anything
+			// already accumulated in Received.
+			select {
+			case <-sp.Stopped():
+				return
+			case <-changed:
+			}
+			continue
+		}
 		select {
 		case <-sp.Stopped():
 			return
@@ -31,17 +43,33 @@ func (sp *SomeProcessor) Process(ch <-chan int) {
 func Example() {
 	p := &SomeProcessor{}
 	ch := make(chan int)
+
+	// Phase one: send 0-4, pausing and resuming mid-stream (Received keeps
+	// whatever it has accumulated so far either way), then Stop. This
+	// goroutine sends nothing beyond item 4, so there is nothing left for it
+	// to race against Process noticing Stop: p.Process(ch) below is
+	// guaranteed to return with exactly [0 1 2 3 4].
 	go func() {
-		for i := 0; i < 10; i++ {
+		for i := 0; i < 5; i++ {
 			ch <- i
-			if i == 4 {
-				p.Stop()
+			if i == 2 {
+				p.Pause()
+				p.Resume()
 			}
 		}
 		p.Stop()
 	}()
 	p.Process(ch)
 	fmt.Println(p.Received)
+
+	// Phase two only starts once the first p.Process(ch) call above has
+	// returned, so it cannot race with phase one.
+	go func() {
+		for i := 5; i < 10; i++ {
+			ch <- i
+		}
+		p.Stop()
+	}()
 	p.Process(ch)
 	fmt.Println(p.Received)
 	// output:
@@ -0,0 +1,134 @@
+package startstopper
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Group is a named collection of StartStoppers that can be started and
+// stopped together, giving callers a supervisor primitive for orchestrating
+// pools of long-lived stateful workers without each one re-implementing the
+// fan-in select boilerplate themselves.
+type Group struct {
+	mu    sync.RWMutex
+	items map[string]*StartStopper
+}
+
+// NewGroup initializes a ready to use, empty Group.
+func NewGroup() *Group {
+	return &Group{items: make(map[string]*StartStopper)}
+}
+
+// Add registers s under name, replacing any existing StartStopper previously
+// registered under the same name. It is safe to call concurrently with the
+// other Group methods, including while callers hold channels returned by
+// AnyStopped or AllStopped.
+func (g *Group) Add(name string, s *StartStopper) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.items == nil {
+		g.items = make(map[string]*StartStopper)
+	}
+	g.items[name] = s
+}
+
+// Remove unregisters the StartStopper registered under name, if any. It does
+// not Stop it first; callers that want a clean shutdown should StopOne
+// before Remove.
+func (g *Group) Remove(name string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.items, name)
+}
+
+// StartAll calls Start on every StartStopper currently in the group.
+func (g *Group) StartAll() {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	for _, s := range g.items {
+		s.Start()
+	}
+}
+
+// StopAll calls Stop on every StartStopper currently in the group.
+func (g *Group) StopAll() {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	for _, s := range g.items {
+		s.Stop()
+	}
+}
+
+// StopOne calls Stop on the StartStopper registered under name, if any. It
+// is a no-op if name is not registered.
+func (g *Group) StopOne(name string) {
+	g.mu.RLock()
+	s, ok := g.items[name]
+	g.mu.RUnlock()
+	if ok {
+		s.Stop()
+	}
+}
+
+// Stopped returns the Stopped channel of the StartStopper registered under
+// name, or nil if name is not registered.
+func (g *Group) Stopped(name string) <-chan struct{} {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	s, ok := g.items[name]
+	if !ok {
+		return nil
+	}
+	return s.Stopped()
+}
+
+// stoppedCases snapshots the group's current members as a set of reflect
+// select cases over their Stopped channels, for use by AnyStopped and
+// AllStopped.
+func (g *Group) stoppedCases() []reflect.SelectCase {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	cases := make([]reflect.SelectCase, 0, len(g.items))
+	for _, s := range g.items {
+		cases = append(cases, reflect.SelectCase{
+			Dir:  reflect.SelectRecv,
+			Chan: reflect.ValueOf(s.Stopped()),
+		})
+	}
+	return cases
+}
+
+// AnyStopped returns a channel that is closed as soon as the first
+// StartStopper currently in the group stops. The channel is regenerated on
+// every call against the group's current members, since the group may be
+// mutated (via Add/Remove) while a caller still holds a previously returned
+// channel. If the group has no members, the returned channel is never
+// closed: there is no first member to stop.
+func (g *Group) AnyStopped() <-chan struct{} {
+	cases := g.stoppedCases()
+	out := make(chan struct{})
+	if len(cases) == 0 {
+		return out
+	}
+	go func() {
+		defer close(out)
+		reflect.Select(cases)
+	}()
+	return out
+}
+
+// AllStopped returns a channel that is closed once every StartStopper
+// currently in the group has stopped. Like AnyStopped, the channel is
+// regenerated on every call against the group's current members.
+func (g *Group) AllStopped() <-chan struct{} {
+	cases := g.stoppedCases()
+	out := make(chan struct{})
+	go func() {
+		defer close(out)
+		for len(cases) > 0 {
+			i, _, _ := reflect.Select(cases)
+			cases = append(cases[:i], cases[i+1:]...)
+		}
+	}()
+	return out
+}
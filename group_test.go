@@ -0,0 +1,116 @@
+package startstopper_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samsalisbury/startstopper"
+)
+
+func TestGroupStartAllStopAll(t *testing.T) {
+	g := startstopper.NewGroup()
+	a := startstopper.NewStartStopper()
+	b := startstopper.NewStartStopper()
+	g.Add("a", a)
+	g.Add("b", b)
+
+	a.Stop()
+	g.StartAll()
+	if a.IsStopped() || b.IsStopped() {
+		t.Fatal("StartAll did not (re)start every member")
+	}
+
+	g.StopAll()
+	if !a.IsStopped() || !b.IsStopped() {
+		t.Fatal("StopAll did not stop every member")
+	}
+}
+
+func TestGroupStopOneAndStopped(t *testing.T) {
+	g := startstopper.NewGroup()
+	a := startstopper.NewStartStopper()
+	b := startstopper.NewStartStopper()
+	g.Add("a", a)
+	g.Add("b", b)
+
+	g.StopOne("a")
+	if !a.IsStopped() {
+		t.Fatal("StopOne did not stop the named member")
+	}
+	if b.IsStopped() {
+		t.Fatal("StopOne stopped an unrelated member")
+	}
+
+	select {
+	case <-g.Stopped("a"):
+	default:
+		t.Fatal("Stopped(\"a\") not closed after StopOne(\"a\")")
+	}
+
+	if g.Stopped("missing") != nil {
+		t.Fatal("Stopped for an unregistered name should be nil")
+	}
+}
+
+func TestGroupAnyStopped(t *testing.T) {
+	g := startstopper.NewGroup()
+	a := startstopper.NewStartStopper()
+	b := startstopper.NewStartStopper()
+	g.Add("a", a)
+	g.Add("b", b)
+
+	any := g.AnyStopped()
+	select {
+	case <-any:
+		t.Fatal("AnyStopped closed before any member stopped")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	b.Stop()
+	select {
+	case <-any:
+	case <-time.After(time.Second):
+		t.Fatal("AnyStopped did not close after a member stopped")
+	}
+}
+
+func TestGroupAllStopped(t *testing.T) {
+	g := startstopper.NewGroup()
+	a := startstopper.NewStartStopper()
+	b := startstopper.NewStartStopper()
+	g.Add("a", a)
+	g.Add("b", b)
+
+	all := g.AllStopped()
+	a.Stop()
+	select {
+	case <-all:
+		t.Fatal("AllStopped closed before every member stopped")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	b.Stop()
+	select {
+	case <-all:
+	case <-time.After(time.Second):
+		t.Fatal("AllStopped did not close after every member stopped")
+	}
+}
+
+func TestGroupAnyStoppedEmptyGroup(t *testing.T) {
+	g := startstopper.NewGroup()
+	select {
+	case <-g.AnyStopped():
+		t.Fatal("AnyStopped on an empty group should never close")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestGroupAllStoppedEmptyGroup(t *testing.T) {
+	g := startstopper.NewGroup()
+	select {
+	case <-g.AllStopped():
+	case <-time.After(time.Second):
+		t.Fatal("AllStopped on an empty group should close immediately (vacuously all stopped)")
+	}
+}
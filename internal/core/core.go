@@ -0,0 +1,188 @@
+// Package core holds the state machine shared by startstopper.StartStopper
+// and the generic startstopper value types, so that both get the same
+// start/stop/pause/resume semantics from a single implementation.
+package core
+
+import "sync"
+
+// State is one of the three states a Core's lifecycle can be in.
+type State int
+
+const (
+	// Running is the zero value, matching the zero-value behaviour of a
+	// Core: an unstarted Core is not considered stopped.
+	Running State = iota
+	Paused
+	Stopped
+)
+
+// String returns a human-readable name for st, for use in logs and tests.
+func (st State) String() string {
+	switch st {
+	case Running:
+		return "Running"
+	case Paused:
+		return "Paused"
+	case Stopped:
+		return "Stopped"
+	default:
+		return "Unknown"
+	}
+}
+
+// Core is the shared state machine underlying startstopper.StartStopper and
+// the generic value.StartStopper: a re-openable "stopped" channel plus
+// a Running/Paused/Stopped state with change notifications. Embedding Core
+// gives a type Start/Stop/Stopped/IsStopped/Pause/Resume/State/StateChanged
+// for free.
+type Core struct {
+	stoppedCh   chan struct{}
+	state       State
+	subscribers []chan State
+	sync.RWMutex
+}
+
+// New returns a Core ready to use in a started state, equivalent to the zero
+// value after a Start call.
+func New() Core {
+	return Core{stoppedCh: make(chan struct{})}
+}
+
+// setStateLocked transitions to new, notifying any StateChanged subscribers.
+// Callers must hold c's write lock.
+func (c *Core) setStateLocked(new State) {
+	if c.state == new {
+		return
+	}
+	c.state = new
+	for _, ch := range c.subscribers {
+		select {
+		case ch <- new:
+		default:
+			// Subscriber hasn't drained the previous state yet: drop it in
+			// favour of the latest one, rather than blocking or growing
+			// unboundedly.
+			select {
+			case <-ch:
+			default:
+			}
+			select {
+			case ch <- new:
+			default:
+			}
+		}
+	}
+}
+
+// Stop closes the channel returned by Stopped since the last Start call.
+func (c *Core) Stop() {
+	c.Lock()
+	defer c.Unlock()
+	select {
+	default:
+		if c.stoppedCh == nil {
+			c.stoppedCh = make(chan struct{})
+		}
+		close(c.stoppedCh)
+	case <-c.stoppedCh:
+		// no-op already closed.
+	}
+	c.setStateLocked(Stopped)
+}
+
+// Start replaces the internal channel with a new open one.
+// All subsequent calls to Stopped will receive this channel.
+func (c *Core) Start() {
+	c.Lock()
+	defer c.Unlock()
+	if c.stoppedCh == nil {
+		c.stoppedCh = make(chan struct{})
+	}
+	select {
+	default:
+	case <-c.stoppedCh:
+		c.stoppedCh = make(chan struct{})
+	}
+	c.setStateLocked(Running)
+}
+
+// Stopped returns a channel that blocks forever until Stop is called.
+func (c *Core) Stopped() <-chan struct{} {
+	c.RLock()
+	defer c.RUnlock()
+	if c.stoppedCh == nil {
+		c.stoppedCh = make(chan struct{})
+	}
+	return c.stoppedCh
+}
+
+// IsStopped is a convenience method that returns true if in stopped state
+// (i.e. the channel returned from Stopped right now is closed), or false
+// otherwise.
+func (c *Core) IsStopped() bool {
+	c.RLock()
+	defer c.RUnlock()
+	if c == nil {
+		return false
+	}
+	select {
+	default:
+		return false
+	case <-c.stoppedCh:
+		return true
+	}
+}
+
+// Pause moves a Running Core into the Paused state. Pause is a no-op if the
+// Core is not currently Running.
+func (c *Core) Pause() {
+	c.Lock()
+	defer c.Unlock()
+	if c.state != Running {
+		return
+	}
+	c.setStateLocked(Paused)
+}
+
+// Resume moves a Paused Core back into the Running state. Resume is a no-op
+// if the Core is not currently Paused.
+func (c *Core) Resume() {
+	c.Lock()
+	defer c.Unlock()
+	if c.state != Paused {
+		return
+	}
+	c.setStateLocked(Running)
+}
+
+// State returns the current Running/Paused/Stopped state.
+func (c *Core) State() State {
+	c.RLock()
+	defer c.RUnlock()
+	return c.state
+}
+
+// StateChanged returns a channel on which every subsequent state transition
+// is delivered, along with an unsubscribe func that must be called once the
+// caller is done watching, to stop the channel being fanned out into (and
+// let it be garbage collected). The returned channel is buffered to depth 1;
+// if the subscriber falls behind, the oldest pending state is dropped in
+// favour of the newest one, so a slow subscriber always catches up to where
+// the Core currently is rather than stalling the transition that produced
+// it.
+func (c *Core) StateChanged() (_ <-chan State, unsubscribe func()) {
+	c.Lock()
+	defer c.Unlock()
+	ch := make(chan State, 1)
+	c.subscribers = append(c.subscribers, ch)
+	return ch, func() {
+		c.Lock()
+		defer c.Unlock()
+		for i, sub := range c.subscribers {
+			if sub == ch {
+				c.subscribers = append(c.subscribers[:i], c.subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
@@ -21,74 +21,140 @@
 // methods to effectively "disabled" until further notice.
 package startstopper
 
-import "sync"
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/samsalisbury/startstopper/internal/core"
+)
+
+// State is one of the three states a StartStopper's lifecycle can be in.
+type State = core.State
+
+// The three states a StartStopper's lifecycle can be in. Running is the zero
+// value, matching the existing zero-value behaviour of a StartStopper: an
+// unstarted StartStopper is not considered stopped.
+const (
+	Running = core.Running
+	Paused  = core.Paused
+	Stopped = core.Stopped
+)
 
 // StartStopper can be used in place of close(chan) to signal that something has
 // finished or stopped. It adds the ability to "reopen" that channel at a later
 // time in a concurrency-safe manner.
+//
+// StartContext and Run additionally let callers drive a StartStopper with a
+// context.Context, so Stop can propagate cancellation into the wrapped work
+// and WaitStopped can block until that work has actually returned, rather
+// than just until Stop was requested.
+//
+// Beyond the binary started/stopped signal, a StartStopper also tracks a
+// three-state lifecycle (Running, Paused, Stopped) via State, Pause, Resume,
+// and StateChanged, so workers can be told to pause and resume without
+// losing whatever state they accumulated while running. This state machine
+// lives in core.Core, which StartStopper embeds; the generic value types in
+// the value subpackage embed the same Core, so they behave identically with
+// respect to starting, stopping, and pausing.
 type StartStopper struct {
-	stoppedCh chan struct{}
-	sync.RWMutex
+	core.Core
+	cancel context.CancelFunc
+	doneCh chan struct{}
+	err    atomic.Value // errHolder
 }
 
+// errHolder wraps an error so it can be stored in an atomic.Value: the zero
+// value (nil error) still has a consistent concrete type across Store calls.
+type errHolder struct{ err error }
+
 // NewStartStopper initializes a ready to use StartStopper in a started state.
 func NewStartStopper() *StartStopper {
-	return &StartStopper{stoppedCh: make(chan struct{})}
+	return &StartStopper{Core: core.New()}
 }
 
-// Stop closes the channel returned by stop since the last Start call.
+// Stop closes the channel returned by Stopped since the last Start call. If
+// the current cycle was started via StartContext, Stop also cancels the
+// context returned from that call.
 func (s *StartStopper) Stop() {
 	s.Lock()
-	defer s.Unlock()
-	select {
-	default:
-		if s.stoppedCh == nil {
-			s.stoppedCh = make(chan struct{})
-		}
-		close(s.stoppedCh)
-	case <-s.stoppedCh:
-		// no-op already closed.
+	cancel := s.cancel
+	s.Unlock()
+	if cancel != nil {
+		cancel()
 	}
+	s.Core.Stop()
 }
 
-// Start replaces the internal channel with a new open one.
-// All subsequent calls to Stopped will receive this channel.
-func (s *StartStopper) Start() {
-	s.Lock()
-	defer s.Unlock()
-	if s.stoppedCh == nil {
-		s.stoppedCh = make(chan struct{})
-	}
-	select {
-	default:
-	case <-s.stoppedCh:
-		s.stoppedCh = make(chan struct{})
+// StartContext behaves like Start, but additionally returns a context.Context
+// derived from parent that is cancelled when Stop is next called. Use this
+// when the wrapped work takes a context.Context and should have cancellation
+// propagated into it on Stop, rather than only observing Stopped().
+//
+// Each call to StartContext begins a new cycle with its own doneCh, even if
+// the previous cycle's goroutine (launched by Run) has not returned yet: that
+// goroutine still holds a reference to its own doneCh and will close it when
+// it returns, regardless of how many further cycles have started since.
+// Callers that need to know when a specific cycle's work has actually
+// finished should call WaitStopped before starting the next cycle.
+func (s *StartStopper) StartContext(parent context.Context) context.Context {
+	if parent == nil {
+		parent = context.Background()
 	}
+	s.Start()
+	ctx, cancel := context.WithCancel(parent)
+	s.Lock()
+	s.cancel = cancel
+	s.doneCh = make(chan struct{})
+	s.Unlock()
+	return ctx
 }
 
-// Stopped returns a channel that blocks forever until Stop is called on this
-// StartStopper.
-func (s *StartStopper) Stopped() <-chan struct{} {
+// Run starts a new cycle via StartContext using ctx as the parent, then runs
+// fn in a goroutine, tracking its completion and result. Run blocks until fn
+// returns and returns fn's error (also available afterwards via Err). Stop
+// cancels the context passed to fn; WaitStopped lets other goroutines block
+// until fn has actually returned, which may be later than Stop was called if
+// fn is in the middle of a blocking operation.
+func (s *StartStopper) Run(ctx context.Context, fn func(context.Context) error) error {
+	runCtx := s.StartContext(ctx)
+
 	s.RLock()
-	defer s.RUnlock()
-	if s.stoppedCh == nil {
-		s.stoppedCh = make(chan struct{})
-	}
-	return s.stoppedCh
+	done := s.doneCh
+	s.RUnlock()
+
+	var err error
+	go func() {
+		err = fn(runCtx)
+		s.err.Store(errHolder{err: err})
+		close(done)
+	}()
+
+	<-done
+	return err
 }
 
-// IsStopped is a convenience method that returns true if in stopped state (i.e.
-// the channel returned from Stopped right now is closed, or true otherwise.
-func (s *StartStopper) IsStopped() bool {
+// WaitStopped blocks until the goroutine launched by the current (or most
+// recent) call to Run has returned. Unlike Stopped, which unblocks as soon as
+// Stop is called, WaitStopped unblocks only once the wrapped work has
+// actually finished. It waits on the cycle begun by the most recent
+// StartContext call; if that cycle was begun by StartContext directly rather
+// than through Run, no goroutine closes it and WaitStopped does not return.
+func (s *StartStopper) WaitStopped() {
 	s.RLock()
-	defer s.RUnlock()
-	if s == nil {
-		return false
+	done := s.doneCh
+	s.RUnlock()
+	if done == nil {
+		return
 	}
-	select {
-	default:
-		return false
-	case <-s.stoppedCh:
-		return true
+	<-done
+}
+
+// Err returns the error returned by the most recently completed Run call, or
+// nil if no Run call has completed yet.
+func (s *StartStopper) Err() error {
+	v := s.err.Load()
+	if v == nil {
+		return nil
 	}
-}
\ No newline at end of file
+	return v.(errHolder).err
+}
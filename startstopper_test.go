@@ -0,0 +1,119 @@
+package startstopper_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/samsalisbury/startstopper"
+)
+
+func TestStartContextCancelledByStop(t *testing.T) {
+	s := startstopper.NewStartStopper()
+	ctx := s.StartContext(context.Background())
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("context cancelled before Stop was called")
+	default:
+	}
+
+	s.Stop()
+
+	select {
+	case <-ctx.Done():
+	default:
+		t.Fatal("context not cancelled after Stop was called")
+	}
+	if got := ctx.Err(); got != context.Canceled {
+		t.Fatalf("ctx.Err() = %v, want %v", got, context.Canceled)
+	}
+}
+
+func TestRunReturnsAndRecordsFnError(t *testing.T) {
+	s := startstopper.NewStartStopper()
+	wantErr := errors.New("boom")
+
+	gotErr := s.Run(context.Background(), func(ctx context.Context) error {
+		return wantErr
+	})
+	if gotErr != wantErr {
+		t.Fatalf("Run() = %v, want %v", gotErr, wantErr)
+	}
+	if got := s.Err(); got != wantErr {
+		t.Fatalf("Err() = %v, want %v", got, wantErr)
+	}
+}
+
+func TestWaitStoppedWaitsForFnToReturn(t *testing.T) {
+	s := startstopper.NewStartStopper()
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	go s.Run(context.Background(), func(ctx context.Context) error {
+		close(started)
+		<-release // simulate work that keeps running past cancellation
+		return nil
+	})
+	<-started
+	s.Stop()
+
+	waitDone := make(chan struct{})
+	go func() {
+		s.WaitStopped()
+		close(waitDone)
+	}()
+
+	select {
+	case <-waitDone:
+		t.Fatal("WaitStopped returned before fn had returned")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+
+	select {
+	case <-waitDone:
+	case <-time.After(time.Second):
+		t.Fatal("WaitStopped did not unblock after fn returned")
+	}
+}
+
+// TestRunRestartBeforePreviousFnReturns is a regression test for a panic
+// where starting a new Run cycle while a previous cycle's fn was still
+// running (e.g. blocked past Stop, as if stuck in a syscall) left both
+// cycles' goroutines closing the same doneCh, causing a "close of closed
+// channel" panic and letting the first Run return before its own fn had
+// actually finished.
+func TestRunRestartBeforePreviousFnReturns(t *testing.T) {
+	s := startstopper.NewStartStopper()
+
+	firstStarted := make(chan struct{})
+	firstUnblock := make(chan struct{})
+	firstDone := make(chan error, 1)
+
+	go func() {
+		firstDone <- s.Run(context.Background(), func(ctx context.Context) error {
+			close(firstStarted)
+			<-ctx.Done()   // observes cancellation from Stop...
+			<-firstUnblock // ...but keeps running past it.
+			return errors.New("first")
+		})
+	}()
+
+	<-firstStarted
+	s.Stop()
+
+	secondErr := s.Run(context.Background(), func(ctx context.Context) error {
+		return errors.New("second")
+	})
+	if secondErr == nil || secondErr.Error() != "second" {
+		t.Fatalf("second Run() = %v, want \"second\"", secondErr)
+	}
+
+	close(firstUnblock)
+	if err := <-firstDone; err == nil || err.Error() != "first" {
+		t.Fatalf("first Run() = %v, want \"first\"", err)
+	}
+}
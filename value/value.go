@@ -0,0 +1,80 @@
+// Package value provides a generic counterpart to startstopper.StartStopper
+// that couples the same start/stop signalling with a one-shot result value
+// carried across each stop cycle, similar in spirit to a Future.
+package value
+
+import (
+	"sync"
+
+	"github.com/samsalisbury/startstopper/internal/core"
+)
+
+// StartStopper couples the start/stop signalling of startstopper.StartStopper
+// with a typed result value, set once per stop cycle via StopWithValue and
+// read back via Value. This lets a long-running goroutine report why it
+// stopped (an error, a final count, a sentinel) without the caller needing a
+// side channel alongside the embedded StartStopper.
+//
+// StartStopper embeds the same core.Core used by startstopper.StartStopper,
+// so it can be embedded in a user struct (as in the startstopper package's
+// SomeProcessor example) and used the same way: call Start, then Stopped or
+// IsStopped to observe completion.
+type StartStopper[T any] struct {
+	core.Core
+	mu    sync.RWMutex
+	value T
+	has   bool
+}
+
+// New initializes a ready to use StartStopper[T] in a started state.
+func New[T any]() *StartStopper[T] {
+	return &StartStopper[T]{Core: core.New()}
+}
+
+// Start behaves like core.Core's Start, additionally clearing any value
+// stored by a previous cycle's StopWithValue, so the next cycle starts
+// fresh. The reset and the underlying state transition happen under the
+// same lock, so a concurrent StopWithValue can never land in between and
+// leave a stale value behind on a Running StartStopper.
+func (s *StartStopper[T]) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var zero T
+	s.value = zero
+	s.has = false
+	s.Core.Start()
+}
+
+// StopWithValue stops the current cycle, as Stop does, and records v as this
+// cycle's result, readable via Value until the next Start. As with Start,
+// the value and the underlying state transition happen under the same lock.
+func (s *StartStopper[T]) StopWithValue(v T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.value = v
+	s.has = true
+	s.Core.Stop()
+}
+
+// Value returns the value most recently passed to StopWithValue in the
+// current cycle, and whether such a value has been set. It returns the zero
+// value and false if the current cycle has not been stopped via
+// StopWithValue.
+func (s *StartStopper[T]) Value() (T, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.value, s.has
+}
+
+// Snapshot returns the current state together with the value and hasValue
+// that Value would return, read under a single lock. Use this instead of
+// calling State and Value separately when a caller needs both to reflect
+// the exact same moment in time (e.g. checking that a Running StartStopper
+// never has a stale value left over from the previous cycle) — State and
+// Value alone are each individually consistent, but two separate calls can
+// straddle an intervening Start or StopWithValue.
+func (s *StartStopper[T]) Snapshot() (state core.State, value T, hasValue bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Core.State(), s.value, s.has
+}
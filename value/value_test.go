@@ -0,0 +1,118 @@
+package value_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/samsalisbury/startstopper/internal/core"
+	"github.com/samsalisbury/startstopper/value"
+)
+
+func TestValueUnsetBeforeStopWithValue(t *testing.T) {
+	s := value.New[int]()
+	if got, ok := s.Value(); ok || got != 0 {
+		t.Fatalf("Value() = (%v, %v), want (0, false) before any StopWithValue", got, ok)
+	}
+}
+
+func TestStopWithValueStopsAndRecordsValue(t *testing.T) {
+	s := value.New[string]()
+	s.StopWithValue("done")
+
+	if !s.IsStopped() {
+		t.Fatal("StopWithValue did not stop the StartStopper")
+	}
+	if got, ok := s.Value(); !ok || got != "done" {
+		t.Fatalf("Value() = (%q, %v), want (\"done\", true)", got, ok)
+	}
+}
+
+func TestStartClearsValueForNextCycle(t *testing.T) {
+	s := value.New[int]()
+	s.StopWithValue(42)
+
+	s.Start()
+	if got, ok := s.Value(); ok || got != 0 {
+		t.Fatalf("Value() after Start() = (%v, %v), want (0, false)", got, ok)
+	}
+	if s.IsStopped() {
+		t.Fatal("Start() did not reopen the StartStopper")
+	}
+
+	s.StopWithValue(7)
+	if got, ok := s.Value(); !ok || got != 7 {
+		t.Fatalf("Value() = (%v, %v), want (7, true)", got, ok)
+	}
+}
+
+// TestConcurrentStartStopWithValueNeverLeavesStaleValue is a regression test
+// for a race where Start() cleared the stored value and flipped the
+// underlying state in two separate critical sections; a StopWithValue
+// landing in the gap between them could leave a stale value/has=true
+// visible on a Running StartStopper. It hammers Start and StopWithValue
+// concurrently while a third goroutine checks the invariant "Running implies
+// Value()'s ok is false".
+func TestConcurrentStartStopWithValueNeverLeavesStaleValue(t *testing.T) {
+	s := value.New[int]()
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	var violations int64
+
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				s.Start()
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+				s.StopWithValue(i)
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if state, _, ok := s.Snapshot(); state == core.Running && ok {
+				atomic.AddInt64(&violations, 1)
+			}
+		}
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	close(stop)
+	wg.Wait()
+
+	if violations != 0 {
+		t.Fatalf("got %d violations of \"Running implies Value() ok is false\", want 0", violations)
+	}
+}
+
+func TestPlainStopLeavesNoValue(t *testing.T) {
+	s := value.New[int]()
+	s.Stop()
+	if !s.IsStopped() {
+		t.Fatal("Stop() did not stop the StartStopper")
+	}
+	if _, ok := s.Value(); ok {
+		t.Fatal("Value() reported a value after a plain Stop() with no StopWithValue")
+	}
+}